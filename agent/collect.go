@@ -0,0 +1,167 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// buildMetricData assembles a MetricData snapshot from the flat samples
+// the collector.Registry returns, reconstructing both the legacy
+// aggregate CPU/Memory/Network fields and the extended per-dimension
+// slices from the same collection pass.
+func buildMetricData(nodeID string, samples []Sample) *MetricData {
+	data := &MetricData{
+		Timestamp: time.Now(),
+		NodeID:    nodeID,
+	}
+
+	interfaces := make(map[string]*InterfaceInfo)
+	disks := make(map[string]*DiskInfo)
+	processes := make(map[string]*ProcessInfo)
+	var sockets SocketInfo
+	haveSockets := false
+	loadByPeriod := map[string]float64{}
+
+	for _, s := range samples {
+		switch s.Name {
+		case "cpu.usage_percent":
+			data.CPU.UsagePercent = s.Value
+		case "cpu.cores":
+			data.CPU.Cores = int(s.Value)
+		case "cpu.load_average":
+			loadByPeriod[s.Labels["period"]] = s.Value
+		case "cpu.core_usage_percent":
+			core, _ := strconv.Atoi(s.Labels["core"])
+			data.CPUCores = append(data.CPUCores, CPUCoreInfo{Core: core, UsagePercent: s.Value})
+
+		case "memory.total_bytes":
+			data.Memory.Total = uint64(s.Value)
+		case "memory.available_bytes":
+			data.Memory.Available = uint64(s.Value)
+		case "memory.used_bytes":
+			data.Memory.Used = uint64(s.Value)
+		case "memory.used_percent":
+			data.Memory.UsedPercent = s.Value
+
+		case "network.bytes_sent", "network.bytes_recv", "network.packets_sent", "network.packets_recv":
+			iface := interfaceFor(interfaces, s.Labels["interface"])
+			applyInterfaceSample(iface, s)
+			applyNetInfoSample(&data.Network, s)
+
+		case "disk.used_percent", "disk.read_count", "disk.write_count", "disk.read_bytes", "disk.write_bytes":
+			applyDiskSample(diskFor(disks, s.Labels["device"], s.Labels["mountpoint"]), s)
+
+		case "process.cpu_percent", "process.rss_bytes":
+			applyProcessSample(processFor(processes, s.Labels["pid"], s.Labels["name"]), s)
+
+		case "socket.count":
+			haveSockets = true
+			switch s.Labels["proto"] {
+			case "tcp":
+				sockets.TCP = int(s.Value)
+			case "udp":
+				sockets.UDP = int(s.Value)
+			}
+		}
+	}
+
+	if len(loadByPeriod) > 0 {
+		data.CPU.LoadAvg = []float64{loadByPeriod["1m"], loadByPeriod["5m"], loadByPeriod["15m"]}
+	}
+	for _, iface := range interfaces {
+		data.Interfaces = append(data.Interfaces, *iface)
+	}
+	for _, d := range disks {
+		data.Disks = append(data.Disks, *d)
+	}
+	for _, p := range processes {
+		data.Processes = append(data.Processes, *p)
+	}
+	if haveSockets {
+		data.Sockets = &sockets
+	}
+
+	return data
+}
+
+func interfaceFor(m map[string]*InterfaceInfo, name string) *InterfaceInfo {
+	iface, ok := m[name]
+	if !ok {
+		iface = &InterfaceInfo{Name: name}
+		m[name] = iface
+	}
+	return iface
+}
+
+func applyInterfaceSample(iface *InterfaceInfo, s Sample) {
+	switch s.Name {
+	case "network.bytes_sent":
+		iface.BytesSent = uint64(s.Value)
+	case "network.bytes_recv":
+		iface.BytesRecv = uint64(s.Value)
+	case "network.packets_sent":
+		iface.PacketsSent = uint64(s.Value)
+	case "network.packets_recv":
+		iface.PacketsRecv = uint64(s.Value)
+	}
+}
+
+// applyNetInfoSample accumulates per-interface deltas into the legacy
+// aggregate Network field, which JSON consumers from before the
+// multi-interface collector expect to be a host-wide total.
+func applyNetInfoSample(n *NetInfo, s Sample) {
+	switch s.Name {
+	case "network.bytes_sent":
+		n.BytesSent += uint64(s.Value)
+	case "network.bytes_recv":
+		n.BytesRecv += uint64(s.Value)
+	case "network.packets_sent":
+		n.PacketsSent += uint64(s.Value)
+	case "network.packets_recv":
+		n.PacketsRecv += uint64(s.Value)
+	}
+}
+
+func diskFor(m map[string]*DiskInfo, device, mountpoint string) *DiskInfo {
+	key := device + "\x00" + mountpoint
+	d, ok := m[key]
+	if !ok {
+		d = &DiskInfo{Device: device, Mountpoint: mountpoint}
+		m[key] = d
+	}
+	return d
+}
+
+func applyDiskSample(d *DiskInfo, s Sample) {
+	switch s.Name {
+	case "disk.used_percent":
+		d.UsedPercent = s.Value
+	case "disk.read_count":
+		d.ReadCount = uint64(s.Value)
+	case "disk.write_count":
+		d.WriteCount = uint64(s.Value)
+	case "disk.read_bytes":
+		d.ReadBytes = uint64(s.Value)
+	case "disk.write_bytes":
+		d.WriteBytes = uint64(s.Value)
+	}
+}
+
+func processFor(m map[string]*ProcessInfo, pid, name string) *ProcessInfo {
+	p, ok := m[pid]
+	if !ok {
+		id, _ := strconv.Atoi(pid)
+		p = &ProcessInfo{PID: int32(id), Name: name}
+		m[pid] = p
+	}
+	return p
+}
+
+func applyProcessSample(p *ProcessInfo, s Sample) {
+	switch s.Name {
+	case "process.cpu_percent":
+		p.CPUPercent = s.Value
+	case "process.rss_bytes":
+		p.RSSBytes = uint64(s.Value)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import "testing"
+
+func TestBuildMetricData(t *testing.T) {
+	samples := []Sample{
+		{Name: "cpu.usage_percent", Value: 42.5},
+		{Name: "cpu.cores", Value: 8},
+		{Name: "cpu.load_average", Value: 1.5, Labels: map[string]string{"period": "1m"}},
+		{Name: "cpu.load_average", Value: 1.2, Labels: map[string]string{"period": "5m"}},
+		{Name: "cpu.load_average", Value: 1.0, Labels: map[string]string{"period": "15m"}},
+		{Name: "cpu.core_usage_percent", Value: 10, Labels: map[string]string{"core": "0"}},
+		{Name: "cpu.core_usage_percent", Value: 20, Labels: map[string]string{"core": "1"}},
+
+		{Name: "memory.total_bytes", Value: 1000},
+		{Name: "memory.available_bytes", Value: 400},
+		{Name: "memory.used_bytes", Value: 600},
+		{Name: "memory.used_percent", Value: 60},
+
+		{Name: "network.bytes_sent", Value: 100, Labels: map[string]string{"interface": "eth0"}},
+		{Name: "network.bytes_recv", Value: 200, Labels: map[string]string{"interface": "eth0"}},
+		{Name: "network.bytes_sent", Value: 10, Labels: map[string]string{"interface": "lo"}},
+		{Name: "network.bytes_recv", Value: 20, Labels: map[string]string{"interface": "lo"}},
+
+		{Name: "disk.used_percent", Value: 55, Labels: map[string]string{"device": "sda1", "mountpoint": "/"}},
+		{Name: "disk.read_bytes", Value: 4096, Labels: map[string]string{"device": "sda1", "mountpoint": "/"}},
+
+		{Name: "process.cpu_percent", Value: 12.5, Labels: map[string]string{"pid": "42", "name": "agent"}},
+		{Name: "process.rss_bytes", Value: 2048, Labels: map[string]string{"pid": "42", "name": "agent"}},
+
+		{Name: "socket.count", Value: 5, Labels: map[string]string{"proto": "tcp"}},
+		{Name: "socket.count", Value: 3, Labels: map[string]string{"proto": "udp"}},
+	}
+
+	data := buildMetricData("node-a", samples)
+
+	if data.NodeID != "node-a" {
+		t.Errorf("NodeID = %q, want %q", data.NodeID, "node-a")
+	}
+
+	if data.CPU.UsagePercent != 42.5 {
+		t.Errorf("CPU.UsagePercent = %v, want 42.5", data.CPU.UsagePercent)
+	}
+	if data.CPU.Cores != 8 {
+		t.Errorf("CPU.Cores = %v, want 8", data.CPU.Cores)
+	}
+	wantLoadAvg := []float64{1.5, 1.2, 1.0}
+	if len(data.CPU.LoadAvg) != 3 || data.CPU.LoadAvg[0] != wantLoadAvg[0] || data.CPU.LoadAvg[1] != wantLoadAvg[1] || data.CPU.LoadAvg[2] != wantLoadAvg[2] {
+		t.Errorf("CPU.LoadAvg = %v, want %v", data.CPU.LoadAvg, wantLoadAvg)
+	}
+	if len(data.CPUCores) != 2 {
+		t.Fatalf("len(CPUCores) = %d, want 2", len(data.CPUCores))
+	}
+
+	if data.Memory.Total != 1000 || data.Memory.Available != 400 || data.Memory.Used != 600 || data.Memory.UsedPercent != 60 {
+		t.Errorf("Memory = %+v, unexpected", data.Memory)
+	}
+
+	// The legacy aggregate Network field must sum across every interface.
+	if data.Network.BytesSent != 110 || data.Network.BytesRecv != 220 {
+		t.Errorf("Network = %+v, want BytesSent=110 BytesRecv=220 (summed across interfaces)", data.Network)
+	}
+	if len(data.Interfaces) != 2 {
+		t.Fatalf("len(Interfaces) = %d, want 2", len(data.Interfaces))
+	}
+	byName := map[string]InterfaceInfo{}
+	for _, iface := range data.Interfaces {
+		byName[iface.Name] = iface
+	}
+	if got := byName["eth0"]; got.BytesSent != 100 || got.BytesRecv != 200 {
+		t.Errorf("Interfaces[eth0] = %+v, want BytesSent=100 BytesRecv=200", got)
+	}
+	if got := byName["lo"]; got.BytesSent != 10 || got.BytesRecv != 20 {
+		t.Errorf("Interfaces[lo] = %+v, want BytesSent=10 BytesRecv=20", got)
+	}
+
+	if len(data.Disks) != 1 {
+		t.Fatalf("len(Disks) = %d, want 1", len(data.Disks))
+	}
+	if d := data.Disks[0]; d.Device != "sda1" || d.Mountpoint != "/" || d.UsedPercent != 55 || d.ReadBytes != 4096 {
+		t.Errorf("Disks[0] = %+v, unexpected", d)
+	}
+
+	if len(data.Processes) != 1 {
+		t.Fatalf("len(Processes) = %d, want 1", len(data.Processes))
+	}
+	if p := data.Processes[0]; p.PID != 42 || p.Name != "agent" || p.CPUPercent != 12.5 || p.RSSBytes != 2048 {
+		t.Errorf("Processes[0] = %+v, want the cpu and rss samples merged into one entry", p)
+	}
+
+	if data.Sockets == nil {
+		t.Fatal("Sockets = nil, want a populated SocketInfo")
+	}
+	if data.Sockets.TCP != 5 || data.Sockets.UDP != 3 {
+		t.Errorf("Sockets = %+v, want TCP=5 UDP=3", data.Sockets)
+	}
+}
+
+func TestBuildMetricData_noSocketSamplesLeavesSocketsNil(t *testing.T) {
+	data := buildMetricData("node-a", []Sample{{Name: "cpu.usage_percent", Value: 1}})
+	if data.Sockets != nil {
+		t.Errorf("Sockets = %+v, want nil when no socket.count samples are present", data.Sockets)
+	}
+}
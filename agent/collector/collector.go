@@ -0,0 +1,76 @@
+// Package collector implements CloudPulse's pluggable collection plane.
+// Each Collector samples one facet of the host (CPU, memory, network,
+// disk, processes, sockets) independently, so operators can enable or
+// disable them individually and new collectors can be added without
+// touching the agent's HTTP surface.
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// Collector samples one facet of the host.
+type Collector interface {
+	// Name identifies the collector in config and error messages.
+	Name() string
+	// Collect returns the collector's samples for one collection cycle.
+	Collect(ctx context.Context) ([]model.Sample, error)
+}
+
+// Config controls which collectors are active. A collector not present in
+// Enabled defaults to enabled, so the zero Config runs everything.
+type Config struct {
+	Enabled map[string]bool
+}
+
+func (c Config) isEnabled(name string) bool {
+	enabled, ok := c.Enabled[name]
+	if !ok {
+		return true
+	}
+	return enabled
+}
+
+// Registry holds the collectors enabled by Config and runs them as a
+// group.
+type Registry struct {
+	collectors []Collector
+}
+
+// NewRegistry builds a Registry containing only the collectors cfg
+// enables.
+func NewRegistry(cfg Config, collectors ...Collector) *Registry {
+	r := &Registry{}
+	for _, c := range collectors {
+		if cfg.isEnabled(c.Name()) {
+			r.collectors = append(r.collectors, c)
+		}
+	}
+	return r
+}
+
+// CollectAll runs every enabled collector and concatenates their samples.
+// A single collector's failure doesn't stop the others; their errors are
+// joined and returned alongside whatever samples were gathered.
+func (r *Registry) CollectAll(ctx context.Context) ([]model.Sample, error) {
+	var samples []model.Sample
+	var errs []string
+
+	for _, c := range r.collectors {
+		s, err := c.Collect(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", c.Name(), err))
+			continue
+		}
+		samples = append(samples, s...)
+	}
+
+	if len(errs) > 0 {
+		return samples, fmt.Errorf("collector errors: %s", strings.Join(errs, "; "))
+	}
+	return samples, nil
+}
@@ -0,0 +1,86 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// fakeCollector is a Collector stub for exercising Registry without
+// touching the host.
+type fakeCollector struct {
+	name    string
+	samples []model.Sample
+	err     error
+}
+
+func (f *fakeCollector) Name() string { return f.name }
+
+func (f *fakeCollector) Collect(ctx context.Context) ([]model.Sample, error) {
+	return f.samples, f.err
+}
+
+func TestConfig_isEnabled(t *testing.T) {
+	cfg := Config{Enabled: map[string]bool{"disk": false, "cpu": true}}
+
+	cases := []struct {
+		name string
+		want bool
+	}{
+		{name: "disk", want: false},
+		{name: "cpu", want: true},
+		{name: "not mentioned defaults to enabled", want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cfg.isEnabled(tc.name); got != tc.want {
+				t.Errorf("isEnabled(%q) = %v, want %v", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewRegistry_disablesByName(t *testing.T) {
+	cfg := Config{Enabled: map[string]bool{"disk": false}}
+	cpu := &fakeCollector{name: "cpu", samples: []model.Sample{{Name: "cpu.usage_percent", Value: 1}}}
+	disk := &fakeCollector{name: "disk", samples: []model.Sample{{Name: "disk.used_percent", Value: 2}}}
+
+	reg := NewRegistry(cfg, cpu, disk)
+
+	samples, err := reg.CollectAll(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAll() returned error: %v", err)
+	}
+	if len(samples) != 1 || samples[0].Name != "cpu.usage_percent" {
+		t.Errorf("CollectAll() = %+v, want only the cpu collector's sample", samples)
+	}
+}
+
+func TestRegistry_CollectAll_partialFailure(t *testing.T) {
+	ok := &fakeCollector{name: "cpu", samples: []model.Sample{{Name: "cpu.usage_percent", Value: 1}}}
+	broken := &fakeCollector{name: "disk", err: errors.New("boom")}
+
+	reg := NewRegistry(Config{}, ok, broken)
+
+	samples, err := reg.CollectAll(context.Background())
+	if err == nil {
+		t.Fatal("CollectAll() returned nil error, want one reporting the broken collector")
+	}
+	if len(samples) != 1 || samples[0].Name != "cpu.usage_percent" {
+		t.Errorf("CollectAll() = %+v, want the healthy collector's sample preserved", samples)
+	}
+}
+
+func TestRegistry_CollectAll_empty(t *testing.T) {
+	reg := NewRegistry(Config{})
+
+	samples, err := reg.CollectAll(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAll() on an empty registry returned error: %v", err)
+	}
+	if len(samples) != 0 {
+		t.Errorf("CollectAll() = %+v, want no samples", samples)
+	}
+}
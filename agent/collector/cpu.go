@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// CPUCollector reports aggregate and per-core CPU usage plus load
+// average.
+type CPUCollector struct{}
+
+func NewCPUCollector() *CPUCollector { return &CPUCollector{} }
+
+func (c *CPUCollector) Name() string { return "cpu" }
+
+func (c *CPUCollector) Collect(ctx context.Context) ([]model.Sample, error) {
+	// A single Second-long sample covers both the aggregate and per-core
+	// figures; sampling twice would make one collection pass take at
+	// least 2s, which starves /metrics/stream's 1s sampler tick.
+	perCore, err := cpu.PercentWithContext(ctx, time.Second, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get per-core CPU usage: %w", err)
+	}
+
+	loadAvg, err := load.AvgWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get load average: %w", err)
+	}
+
+	count, err := cpu.CountsWithContext(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CPU count: %w", err)
+	}
+
+	samples := []model.Sample{
+		{Name: "cpu.usage_percent", Value: average(perCore)},
+		{Name: "cpu.cores", Value: float64(count)},
+		{Name: "cpu.load_average", Value: loadAvg.Load1, Labels: map[string]string{"period": "1m"}},
+		{Name: "cpu.load_average", Value: loadAvg.Load5, Labels: map[string]string{"period": "5m"}},
+		{Name: "cpu.load_average", Value: loadAvg.Load15, Labels: map[string]string{"period": "15m"}},
+	}
+
+	for i, pct := range perCore {
+		samples = append(samples, model.Sample{
+			Name:   "cpu.core_usage_percent",
+			Value:  pct,
+			Labels: map[string]string{"core": strconv.Itoa(i)},
+		})
+	}
+
+	return samples, nil
+}
+
+// average returns the mean of pcts, or 0 if it's empty.
+func average(pcts []float64) float64 {
+	if len(pcts) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, p := range pcts {
+		sum += p
+	}
+	return sum / float64(len(pcts))
+}
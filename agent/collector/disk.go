@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// DiskCollector reports per-mount usage and cumulative IOPS counters.
+type DiskCollector struct{}
+
+func NewDiskCollector() *DiskCollector { return &DiskCollector{} }
+
+func (c *DiskCollector) Name() string { return "disk" }
+
+func (c *DiskCollector) Collect(ctx context.Context) ([]model.Sample, error) {
+	partitions, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list disk partitions: %w", err)
+	}
+
+	ioCounters, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disk IO counters: %w", err)
+	}
+
+	var samples []model.Sample
+	for _, p := range partitions {
+		usage, err := disk.UsageWithContext(ctx, p.Mountpoint)
+		if err != nil {
+			continue
+		}
+
+		labels := map[string]string{"device": p.Device, "mountpoint": p.Mountpoint}
+		samples = append(samples, model.Sample{Name: "disk.used_percent", Value: usage.UsedPercent, Labels: labels})
+
+		io, ok := ioCounters[deviceBase(p.Device)]
+		if !ok {
+			continue
+		}
+		samples = append(samples,
+			model.Sample{Name: "disk.read_count", Value: float64(io.ReadCount), Labels: labels},
+			model.Sample{Name: "disk.write_count", Value: float64(io.WriteCount), Labels: labels},
+			model.Sample{Name: "disk.read_bytes", Value: float64(io.ReadBytes), Labels: labels},
+			model.Sample{Name: "disk.write_bytes", Value: float64(io.WriteBytes), Labels: labels},
+		)
+	}
+
+	return samples, nil
+}
+
+// deviceBase strips everything up to and including the last "/" so a
+// partition's Device (e.g. "/dev/sda1") matches the keys IOCounters
+// returns (e.g. "sda1").
+func deviceBase(device string) string {
+	for i := len(device) - 1; i >= 0; i-- {
+		if device[i] == '/' {
+			return device[i+1:]
+		}
+	}
+	return device
+}
@@ -0,0 +1,31 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// MemoryCollector reports virtual memory usage.
+type MemoryCollector struct{}
+
+func NewMemoryCollector() *MemoryCollector { return &MemoryCollector{} }
+
+func (c *MemoryCollector) Name() string { return "memory" }
+
+func (c *MemoryCollector) Collect(ctx context.Context) ([]model.Sample, error) {
+	info, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory metrics: %w", err)
+	}
+
+	return []model.Sample{
+		{Name: "memory.total_bytes", Value: float64(info.Total)},
+		{Name: "memory.available_bytes", Value: float64(info.Available)},
+		{Name: "memory.used_bytes", Value: float64(info.Used)},
+		{Name: "memory.used_percent", Value: info.UsedPercent},
+	}, nil
+}
@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/net"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// NetworkCollector reports per-interface byte/packet deltas since the
+// previous collection, across all interfaces. Earlier code hard-coded
+// "eth0"/"en0" and broke out of the loop after the first match, silently
+// dropping every other interface's stats; this keeps one lastStats entry
+// per interface instead.
+type NetworkCollector struct {
+	mu   sync.Mutex
+	last map[string]net.IOCountersStat
+}
+
+func NewNetworkCollector() *NetworkCollector {
+	return &NetworkCollector{last: make(map[string]net.IOCountersStat)}
+}
+
+func (c *NetworkCollector) Name() string { return "network" }
+
+func (c *NetworkCollector) Collect(ctx context.Context) ([]model.Sample, error) {
+	stats, err := net.IOCountersWithContext(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network metrics: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var samples []model.Sample
+	for _, stat := range stats {
+		labels := map[string]string{"interface": stat.Name}
+		var sent, recv, packetsSent, packetsRecv uint64
+		if last, ok := c.last[stat.Name]; ok {
+			sent = stat.BytesSent - last.BytesSent
+			recv = stat.BytesRecv - last.BytesRecv
+			packetsSent = stat.PacketsSent - last.PacketsSent
+			packetsRecv = stat.PacketsRecv - last.PacketsRecv
+		}
+		c.last[stat.Name] = stat
+
+		samples = append(samples,
+			model.Sample{Name: "network.bytes_sent", Value: float64(sent), Labels: labels},
+			model.Sample{Name: "network.bytes_recv", Value: float64(recv), Labels: labels},
+			model.Sample{Name: "network.packets_sent", Value: float64(packetsSent), Labels: labels},
+			model.Sample{Name: "network.packets_recv", Value: float64(packetsRecv), Labels: labels},
+		)
+	}
+
+	return samples, nil
+}
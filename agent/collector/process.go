@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// defaultTopN bounds how many processes each ranking (CPU, RSS) reports,
+// so a busy host doesn't turn this into an unbounded dump of every PID.
+const defaultTopN = 10
+
+// ProcessCollector reports the top-N processes by CPU and by RSS.
+type ProcessCollector struct {
+	topN int
+}
+
+func NewProcessCollector() *ProcessCollector {
+	return &ProcessCollector{topN: defaultTopN}
+}
+
+func (c *ProcessCollector) Name() string { return "process" }
+
+func (c *ProcessCollector) Collect(ctx context.Context) ([]model.Sample, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	type entry struct {
+		pid        int32
+		name       string
+		cpuPercent float64
+		rss        uint64
+	}
+
+	entries := make([]entry, 0, len(procs))
+	for _, p := range procs {
+		name, err := p.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		cpuPercent, err := p.CPUPercentWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		memInfo, err := p.MemoryInfoWithContext(ctx)
+		if err != nil || memInfo == nil {
+			continue
+		}
+		entries = append(entries, entry{pid: p.Pid, name: name, cpuPercent: cpuPercent, rss: memInfo.RSS})
+	}
+
+	var samples []model.Sample
+
+	byCPU := append([]entry(nil), entries...)
+	sort.Slice(byCPU, func(i, j int) bool { return byCPU[i].cpuPercent > byCPU[j].cpuPercent })
+	for _, e := range topN(byCPU, c.topN) {
+		labels := map[string]string{"pid": strconv.Itoa(int(e.pid)), "name": e.name}
+		samples = append(samples, model.Sample{Name: "process.cpu_percent", Value: e.cpuPercent, Labels: labels})
+	}
+
+	byRSS := append([]entry(nil), entries...)
+	sort.Slice(byRSS, func(i, j int) bool { return byRSS[i].rss > byRSS[j].rss })
+	for _, e := range topN(byRSS, c.topN) {
+		labels := map[string]string{"pid": strconv.Itoa(int(e.pid)), "name": e.name}
+		samples = append(samples, model.Sample{Name: "process.rss_bytes", Value: float64(e.rss), Labels: labels})
+	}
+
+	return samples, nil
+}
+
+func topN[T any](s []T, n int) []T {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/net"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// SocketCollector reports the system-wide count of open TCP and UDP
+// sockets.
+type SocketCollector struct{}
+
+func NewSocketCollector() *SocketCollector { return &SocketCollector{} }
+
+func (c *SocketCollector) Name() string { return "socket" }
+
+func (c *SocketCollector) Collect(ctx context.Context) ([]model.Sample, error) {
+	tcp, err := net.ConnectionsWithContext(ctx, "tcp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TCP socket count: %w", err)
+	}
+
+	udp, err := net.ConnectionsWithContext(ctx, "udp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get UDP socket count: %w", err)
+	}
+
+	return []model.Sample{
+		{Name: "socket.count", Value: float64(len(tcp)), Labels: map[string]string{"proto": "tcp"}},
+		{Name: "socket.count", Value: float64(len(udp)), Labels: map[string]string{"proto": "udp"}},
+	}, nil
+}
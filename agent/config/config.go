@@ -0,0 +1,99 @@
+// Package config loads the agent's configuration from a YAML or JSON
+// file, superseding the ad-hoc NODE_ID/PORT environment variables: those
+// are now only used as fallbacks when no config file is given, or to fill
+// in whatever the file leaves unset.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultShutdownTimeout = 10 * time.Second
+
+// Config is the agent's full configuration.
+type Config struct {
+	NodeID          string
+	Port            string
+	ShutdownTimeout time.Duration
+}
+
+// configFile mirrors Config's on-disk shape. ShutdownTimeout is read as a
+// human duration string (e.g. "30s") and parsed with time.ParseDuration,
+// the same way CLOUDPULSE_EXPORT_INTERVAL is parsed elsewhere in the
+// agent, rather than unmarshaled straight into a time.Duration: JSON
+// rejects a string there outright, and YAML happily parses a bare
+// "shutdown_timeout: 30" as 30 nanoseconds.
+type configFile struct {
+	NodeID          string `yaml:"node_id" json:"node_id"`
+	Port            string `yaml:"port" json:"port"`
+	ShutdownTimeout string `yaml:"shutdown_timeout" json:"shutdown_timeout"`
+}
+
+// Load reads Config from path, detecting YAML or JSON by its extension.
+// An empty path is valid and yields a Config built entirely from the
+// NODE_ID/PORT environment variables and defaults, matching the agent's
+// behavior from before this loader existed.
+func Load(path string) (*Config, error) {
+	cfg := &Config{
+		NodeID: os.Getenv("NODE_ID"),
+		Port:   os.Getenv("PORT"),
+	}
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config %s: %w", path, err)
+		}
+
+		var cf configFile
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".yaml", ".yml":
+			if err := yaml.Unmarshal(raw, &cf); err != nil {
+				return nil, fmt.Errorf("parse YAML config %s: %w", path, err)
+			}
+		case ".json":
+			if err := json.Unmarshal(raw, &cf); err != nil {
+				return nil, fmt.Errorf("parse JSON config %s: %w", path, err)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported config file extension %q", ext)
+		}
+
+		if cf.NodeID != "" {
+			cfg.NodeID = cf.NodeID
+		}
+		if cf.Port != "" {
+			cfg.Port = cf.Port
+		}
+		if cf.ShutdownTimeout != "" {
+			d, err := time.ParseDuration(cf.ShutdownTimeout)
+			if err != nil {
+				return nil, fmt.Errorf("parse shutdown_timeout %q: %w", cf.ShutdownTimeout, err)
+			}
+			cfg.ShutdownTimeout = d
+		}
+	}
+
+	if cfg.NodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("determine node ID: %w", err)
+		}
+		cfg.NodeID = hostname
+	}
+	if cfg.Port == "" {
+		cfg.Port = "8080"
+	}
+	if cfg.ShutdownTimeout == 0 {
+		cfg.ShutdownTimeout = defaultShutdownTimeout
+	}
+
+	return cfg, nil
+}
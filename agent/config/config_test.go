@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name        string
+		file        string // file content; empty means no config file
+		ext         string
+		env         map[string]string
+		wantPort    string
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "no config file falls back to env and defaults",
+			env:         map[string]string{"NODE_ID": "node-a", "PORT": "9090"},
+			wantPort:    "9090",
+			wantTimeout: defaultShutdownTimeout,
+		},
+		{
+			name:        "no config file and no env uses defaults",
+			wantPort:    "8080",
+			wantTimeout: defaultShutdownTimeout,
+		},
+		{
+			name:        "YAML file overrides env and parses shutdown_timeout",
+			file:        "node_id: node-b\nport: \"9091\"\nshutdown_timeout: \"30s\"\n",
+			ext:         ".yaml",
+			env:         map[string]string{"PORT": "9090"},
+			wantPort:    "9091",
+			wantTimeout: 30 * time.Second,
+		},
+		{
+			name:        "JSON file overrides env and parses shutdown_timeout",
+			file:        `{"node_id": "node-c", "port": "9092", "shutdown_timeout": "1m"}`,
+			ext:         ".json",
+			wantPort:    "9092",
+			wantTimeout: time.Minute,
+		},
+		{
+			name:        "YAML file omitting shutdown_timeout keeps the default",
+			file:        "node_id: node-d\n",
+			ext:         ".yaml",
+			wantPort:    "8080",
+			wantTimeout: defaultShutdownTimeout,
+		},
+		{
+			name:    "invalid shutdown_timeout string is an error",
+			file:    `{"shutdown_timeout": "not-a-duration"}`,
+			ext:     ".json",
+			wantErr: true,
+		},
+		{
+			name:    "bare numeric shutdown_timeout is rejected, not silently taken as nanoseconds",
+			file:    "shutdown_timeout: 30\n",
+			ext:     ".yaml",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported extension is an error",
+			file:    "node_id: node-e\n",
+			ext:     ".toml",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Isolate from whatever NODE_ID/PORT happen to be set in the
+			// ambient test environment.
+			t.Setenv("NODE_ID", tc.env["NODE_ID"])
+			t.Setenv("PORT", tc.env["PORT"])
+
+			path := ""
+			if tc.file != "" {
+				path = filepath.Join(t.TempDir(), "config"+tc.ext)
+				if err := os.WriteFile(path, []byte(tc.file), 0o644); err != nil {
+					t.Fatalf("write config file: %v", err)
+				}
+			}
+
+			cfg, err := Load(path)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Load(%q) = nil error, want one", path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load(%q) returned error: %v", path, err)
+			}
+
+			if cfg.Port != tc.wantPort {
+				t.Errorf("Port = %q, want %q", cfg.Port, tc.wantPort)
+			}
+			if cfg.ShutdownTimeout != tc.wantTimeout {
+				t.Errorf("ShutdownTimeout = %v, want %v", cfg.ShutdownTimeout, tc.wantTimeout)
+			}
+		})
+	}
+}
+
+func TestLoad_defaultsNodeIDToHostname(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load(\"\") returned error: %v", err)
+	}
+	if cfg.NodeID == "" {
+		t.Error("NodeID should default to the hostname, got empty string")
+	}
+}
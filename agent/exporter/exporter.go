@@ -0,0 +1,69 @@
+// Package exporter implements push-mode delivery of CloudPulse metrics to
+// external systems, complementing the pull-based /metrics and
+// /metrics.prom endpoints. An Agent drives any number of Exporters from a
+// background goroutine on a fixed interval so operators can pick whichever
+// subset fits their stack.
+package exporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// Exporter pushes a batch of metric samples to an external system.
+type Exporter interface {
+	// Name identifies the exporter in logs and error messages.
+	Name() string
+	// Export delivers data, returning an error if the destination could
+	// not be reached or rejected the batch.
+	Export(ctx context.Context, data []*model.MetricData) error
+}
+
+// Manager drives a set of Exporters on a fixed interval, logging and
+// swallowing individual exporter errors so one failing destination
+// doesn't stop delivery to the others.
+type Manager struct {
+	exporters []Exporter
+	interval  time.Duration
+	logger    *logrus.Logger
+}
+
+// NewManager builds a Manager that exports on the given interval. A
+// Manager with no exporters is valid and simply does nothing when run.
+func NewManager(interval time.Duration, logger *logrus.Logger, exporters ...Exporter) *Manager {
+	return &Manager{exporters: exporters, interval: interval, logger: logger}
+}
+
+// Run collects one MetricData sample per tick via collect and pushes it to
+// every configured exporter, blocking until ctx is canceled.
+func (m *Manager) Run(ctx context.Context, collect func() (*model.MetricData, error)) {
+	if len(m.exporters) == 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			data, err := collect()
+			if err != nil {
+				m.logger.WithError(err).Error("exporter manager: collect failed")
+				continue
+			}
+			for _, exp := range m.exporters {
+				if err := exp.Export(ctx, []*model.MetricData{data}); err != nil {
+					m.logger.WithError(err).WithField("exporter", exp.Name()).Error("export failed")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// OTLPExporter pushes samples to an OpenTelemetry collector's OTLP/HTTP
+// metrics endpoint.
+type OTLPExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewOTLPExporter builds an exporter that posts OTLP metric requests to
+// endpoint (typically ".../v1/metrics" on an otel-collector).
+func NewOTLPExporter(endpoint string) *OTLPExporter {
+	return &OTLPExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (e *OTLPExporter) Name() string { return "otlp_http" }
+
+func (e *OTLPExporter) Export(ctx context.Context, data []*model.MetricData) error {
+	body, err := json.Marshal(buildOTLPRequest(data))
+	if err != nil {
+		return fmt.Errorf("marshal OTLP request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send OTLP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("OTLP collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// The otlp* types below are a minimal subset of
+// opentelemetry.proto.collector.metrics.v1.ExportMetricsServiceRequest,
+// expressed as plain structs so this exporter doesn't need to pull in the
+// full OTLP protobuf/SDK dependency tree for a handful of gauges.
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpAttribute struct {
+	Key   string        `json:"key"`
+	Value otlpAttrValue `json:"value"`
+}
+
+type otlpAttrValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpScopeMetrics struct {
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpMetric struct {
+	Name  string    `json:"name"`
+	Gauge otlpGauge `json:"gauge"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpDataPoint struct {
+	TimeUnixNano string  `json:"timeUnixNano"`
+	AsDouble     float64 `json:"asDouble"`
+}
+
+func buildOTLPRequest(data []*model.MetricData) otlpRequest {
+	var resources []otlpResourceMetrics
+	for _, d := range data {
+		ts := fmt.Sprintf("%d", d.Timestamp.UnixNano())
+		point := func(v float64) otlpGauge {
+			return otlpGauge{DataPoints: []otlpDataPoint{{TimeUnixNano: ts, AsDouble: v}}}
+		}
+		resources = append(resources, otlpResourceMetrics{
+			Resource: otlpResource{Attributes: []otlpAttribute{
+				{Key: "node_id", Value: otlpAttrValue{StringValue: d.NodeID}},
+			}},
+			ScopeMetrics: []otlpScopeMetrics{{Metrics: []otlpMetric{
+				{Name: "cloudpulse.cpu.usage_percent", Gauge: point(d.CPU.UsagePercent)},
+				{Name: "cloudpulse.memory.used_percent", Gauge: point(d.Memory.UsedPercent)},
+			}}},
+		})
+	}
+	return otlpRequest{ResourceMetrics: resources}
+}
@@ -0,0 +1,92 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// RemoteWriteExporter pushes samples to a Prometheus remote-write
+// endpoint (Cortex, Mimir, Thanos receive, ...).
+type RemoteWriteExporter struct {
+	url    string
+	client *http.Client
+}
+
+// NewRemoteWriteExporter builds an exporter that posts to the given
+// remote-write URL.
+func NewRemoteWriteExporter(url string) *RemoteWriteExporter {
+	return &RemoteWriteExporter{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (e *RemoteWriteExporter) Name() string { return "prometheus_remote_write" }
+
+func (e *RemoteWriteExporter) Export(ctx context.Context, data []*model.MetricData) error {
+	req := &prompb.WriteRequest{}
+	for _, d := range data {
+		req.Timeseries = append(req.Timeseries, seriesFor(d)...)
+	}
+
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal remote-write request: %w", err)
+	}
+	compressed := snappy.Encode(nil, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(compressed))
+	if err != nil {
+		return fmt.Errorf("build remote-write request: %w", err)
+	}
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("send remote-write request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("remote-write endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+func seriesFor(d *model.MetricData) []prompb.TimeSeries {
+	ts := d.Timestamp.UnixMilli()
+
+	sample := func(name string, value float64, extraLabels ...string) prompb.TimeSeries {
+		labels := []prompb.Label{
+			{Name: "__name__", Value: name},
+			{Name: "node_id", Value: d.NodeID},
+		}
+		for i := 0; i+1 < len(extraLabels); i += 2 {
+			labels = append(labels, prompb.Label{Name: extraLabels[i], Value: extraLabels[i+1]})
+		}
+		return prompb.TimeSeries{
+			Labels:  labels,
+			Samples: []prompb.Sample{{Value: value, Timestamp: ts}},
+		}
+	}
+
+	return []prompb.TimeSeries{
+		sample("cloudpulse_cpu_usage_percent", d.CPU.UsagePercent),
+		sample("cloudpulse_memory_used_percent", d.Memory.UsedPercent),
+		sample("cloudpulse_network_bytes_total", float64(d.Network.BytesSent), "direction", "sent"),
+		sample("cloudpulse_network_bytes_total", float64(d.Network.BytesRecv), "direction", "recv"),
+	}
+}
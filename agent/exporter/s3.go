@@ -0,0 +1,123 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/srdarkseer/CloudPulse/agent/model"
+)
+
+// s3Workers is the size of the upload worker pool, modeled after the
+// directory-upload-manager pattern of a small fixed pool draining a job
+// queue rather than spawning a goroutine per upload.
+const s3Workers = 4
+
+// s3MaxAttempts bounds the exponential backoff retry loop so a
+// persistently unreachable bucket can't wedge a worker forever.
+const s3MaxAttempts = 5
+
+// PutObjectAPI is the subset of the S3 client S3Uploader needs, so tests
+// can substitute a fake.
+type PutObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3Uploader batches metric samples and flushes them to an S3-compatible
+// bucket as JSON objects. Export enqueues a batch and returns immediately;
+// a worker pool performs the actual upload with exponential backoff
+// retry, so a slow or backed-off bucket never blocks the export interval.
+type S3Uploader struct {
+	bucket string
+	prefix string
+	client PutObjectAPI
+	logger *logrus.Logger
+	jobs   chan []*model.MetricData
+}
+
+// NewS3Uploader builds an uploader writing JSON batches under prefix in
+// bucket, and starts its worker pool. The workers share ctx with the
+// caller: canceling it (agent shutdown) aborts any in-flight retry
+// backoff and stops the workers, rather than leaking them or letting a
+// retry loop run past shutdown.
+func NewS3Uploader(ctx context.Context, bucket, prefix string, client PutObjectAPI, logger *logrus.Logger) *S3Uploader {
+	u := &S3Uploader{
+		bucket: bucket,
+		prefix: prefix,
+		client: client,
+		logger: logger,
+		jobs:   make(chan []*model.MetricData, 16),
+	}
+	for i := 0; i < s3Workers; i++ {
+		go u.worker(ctx)
+	}
+	return u
+}
+
+func (u *S3Uploader) Name() string { return "s3_batch_uploader" }
+
+// Export enqueues data for asynchronous upload. It returns an error only
+// if the queue is full, so a caller on a fixed export interval can log
+// and move on rather than blocking.
+func (u *S3Uploader) Export(ctx context.Context, data []*model.MetricData) error {
+	select {
+	case u.jobs <- data:
+		return nil
+	default:
+		return fmt.Errorf("s3 uploader: queue full, dropping batch of %d samples", len(data))
+	}
+}
+
+func (u *S3Uploader) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case batch := <-u.jobs:
+			if err := u.uploadWithRetry(ctx, batch); err != nil {
+				u.logger.WithError(err).Error("s3 uploader: giving up on batch")
+			}
+		}
+	}
+}
+
+func (u *S3Uploader) uploadWithRetry(ctx context.Context, batch []*model.MetricData) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("marshal batch: %w", err)
+	}
+	key := fmt.Sprintf("%s/%s.json", u.prefix, batch[0].Timestamp.UTC().Format("20060102T150405.000000000Z"))
+
+	var lastErr error
+	for attempt := 0; attempt < s3MaxAttempts; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		_, err := u.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: &u.bucket,
+			Key:    &key,
+			Body:   bytes.NewReader(body),
+		})
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("upload failed after %d attempts: %w", s3MaxAttempts, lastErr)
+}
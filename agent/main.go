@@ -1,144 +1,194 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gorilla/mux"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
-)
-
-// MetricData represents collected system metrics
-type MetricData struct {
-	Timestamp   time.Time `json:"timestamp"`
-	NodeID      string    `json:"node_id"`
-	CPU         CPUInfo   `json:"cpu"`
-	Memory      MemInfo   `json:"memory"`
-	Network     NetInfo   `json:"network"`
-}
 
-type CPUInfo struct {
-	UsagePercent float64 `json:"usage_percent"`
-	LoadAvg      []float64 `json:"load_avg"`
-	Cores        int     `json:"cores"`
-}
+	"github.com/srdarkseer/CloudPulse/agent/collector"
+	"github.com/srdarkseer/CloudPulse/agent/config"
+	"github.com/srdarkseer/CloudPulse/agent/exporter"
+	"github.com/srdarkseer/CloudPulse/agent/metrics"
+	"github.com/srdarkseer/CloudPulse/agent/model"
+	"github.com/srdarkseer/CloudPulse/agent/service"
+	"github.com/srdarkseer/CloudPulse/agent/stats"
+)
 
-type MemInfo struct {
-	Total       uint64  `json:"total"`
-	Available   uint64  `json:"available"`
-	Used        uint64  `json:"used"`
-	UsedPercent float64 `json:"used_percent"`
-}
-
-type NetInfo struct {
-	BytesSent   uint64 `json:"bytes_sent"`
-	BytesRecv   uint64 `json:"bytes_recv"`
-	PacketsSent uint64 `json:"packets_sent"`
-	PacketsRecv uint64 `json:"packets_recv"`
-}
+// MetricData and its nested types live in package model so that
+// subpackages (exporter, collector) can share them without importing
+// package main.
+type MetricData = model.MetricData
+type CPUInfo = model.CPUInfo
+type MemInfo = model.MemInfo
+type NetInfo = model.NetInfo
+type Sample = model.Sample
+type CPUCoreInfo = model.CPUCoreInfo
+type InterfaceInfo = model.InterfaceInfo
+type DiskInfo = model.DiskInfo
+type ProcessInfo = model.ProcessInfo
+type SocketInfo = model.SocketInfo
 
 type Agent struct {
-	nodeID     string
-	collector  *MetricCollector
-	server     *http.Server
-	logger     *logrus.Logger
+	nodeID       string
+	collector    *MetricCollector
+	server       *http.Server
+	logger       *logrus.Logger
+	promRegistry *prometheus.Registry
+	httpMetrics  *metrics.HTTPMetrics
+	billing      *stats.Registry
+	tenantAuth   *stats.TenantAuth
+	stream       *broadcaster
+	collectors   *collector.Registry
+	port         string
+	ready        atomic.Bool
 }
 
 type MetricCollector struct {
-	lastNetStats map[string]net.IOCountersStat
+	last    atomic.Pointer[MetricData]
+	billing *stats.Registry
 }
 
-func NewAgent(nodeID string) *Agent {
+func NewAgent(nodeID, port string) *Agent {
 	logger := logrus.New()
 	logger.SetLevel(logrus.InfoLevel)
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	promRegistry := prometheus.NewRegistry()
+	httpMetrics := metrics.NewHTTPMetrics(promRegistry)
+	logger.AddHook(httpMetrics.Hook())
+
+	billing := stats.NewRegistry()
+	metricCollector := NewMetricCollector(billing)
+	promRegistry.MustRegister(metricCollector)
+
+	var tenantAuth *stats.TenantAuth
+	if raw := os.Getenv("CLOUDPULSE_TENANT_TOKENS"); raw != "" {
+		tenantAuth = stats.NewTenantAuth(stats.ParseTokens(raw))
+	}
+
 	return &Agent{
-		nodeID:    nodeID,
-		collector: NewMetricCollector(),
-		logger:    logger,
+		nodeID:       nodeID,
+		collector:    metricCollector,
+		logger:       logger,
+		promRegistry: promRegistry,
+		httpMetrics:  httpMetrics,
+		billing:      billing,
+		tenantAuth:   tenantAuth,
+		stream:       newBroadcaster(),
+		collectors:   newCollectorRegistry(),
+		port:         port,
 	}
 }
 
-func NewMetricCollector() *MetricCollector {
-	return &MetricCollector{
-		lastNetStats: make(map[string]net.IOCountersStat),
+// newCollectorRegistry builds the collector plane, disabling whatever
+// collector names appear in the comma-separated CLOUDPULSE_DISABLED_COLLECTORS
+// environment variable.
+func newCollectorRegistry() *collector.Registry {
+	cfg := collector.Config{Enabled: make(map[string]bool)}
+	for _, name := range strings.Split(os.Getenv("CLOUDPULSE_DISABLED_COLLECTORS"), ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			cfg.Enabled[name] = false
+		}
 	}
+
+	return collector.NewRegistry(cfg,
+		collector.NewCPUCollector(),
+		collector.NewMemoryCollector(),
+		collector.NewNetworkCollector(),
+		collector.NewDiskCollector(),
+		collector.NewProcessCollector(),
+		collector.NewSocketCollector(),
+	)
 }
 
-func (a *Agent) collectMetrics() (*MetricData, error) {
-	// Collect CPU metrics
-	cpuPercent, err := cpu.Percent(time.Second, false)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get CPU metrics: %w", err)
+func NewMetricCollector(billing *stats.Registry) *MetricCollector {
+	return &MetricCollector{
+		billing: billing,
 	}
+}
 
-	loadAvg, err := cpu.LoadAvg()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get load average: %w", err)
-	}
+// Update records one authenticated request against the per-tenant billing
+// registry. It is the entry point the billing middleware calls after
+// resolving a bearer token to a tenant ID.
+func (c *MetricCollector) Update(tenant, op string, bytesIn, bytesOut int64, isError bool) {
+	c.billing.Update(tenant, op, bytesIn, bytesOut, isError)
+}
 
-	cpuCount, err := cpu.Counts(true)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get CPU count: %w", err)
+// Describe implements prometheus.Collector, reporting the same metric
+// descriptors used by Collect.
+func (c *MetricCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metrics.CPUUsagePercent
+	ch <- metrics.CPULoad
+	ch <- metrics.CPUCores
+	ch <- metrics.MemTotal
+	ch <- metrics.MemAvailable
+	ch <- metrics.MemUsed
+	ch <- metrics.MemUsedPercent
+	ch <- metrics.NetBytes
+	ch <- metrics.NetPackets
+}
+
+// Collect implements prometheus.Collector, feeding /metrics.prom from the
+// same MetricData snapshot served by the JSON /metrics handler.
+func (c *MetricCollector) Collect(ch chan<- prometheus.Metric) {
+	data := c.last.Load()
+	if data == nil {
+		return
 	}
 
-	// Collect memory metrics
-	memInfo, err := mem.VirtualMemory()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get memory metrics: %w", err)
+	ch <- prometheus.MustNewConstMetric(metrics.CPUUsagePercent, prometheus.GaugeValue, data.CPU.UsagePercent)
+	ch <- prometheus.MustNewConstMetric(metrics.CPUCores, prometheus.GaugeValue, float64(data.CPU.Cores))
+	if len(data.CPU.LoadAvg) == 3 {
+		ch <- prometheus.MustNewConstMetric(metrics.CPULoad, prometheus.GaugeValue, data.CPU.LoadAvg[0], "1m")
+		ch <- prometheus.MustNewConstMetric(metrics.CPULoad, prometheus.GaugeValue, data.CPU.LoadAvg[1], "5m")
+		ch <- prometheus.MustNewConstMetric(metrics.CPULoad, prometheus.GaugeValue, data.CPU.LoadAvg[2], "15m")
 	}
 
-	// Collect network metrics
-	netStats, err := net.IOCounters(true)
+	ch <- prometheus.MustNewConstMetric(metrics.MemTotal, prometheus.GaugeValue, float64(data.Memory.Total))
+	ch <- prometheus.MustNewConstMetric(metrics.MemAvailable, prometheus.GaugeValue, float64(data.Memory.Available))
+	ch <- prometheus.MustNewConstMetric(metrics.MemUsed, prometheus.GaugeValue, float64(data.Memory.Used))
+	ch <- prometheus.MustNewConstMetric(metrics.MemUsedPercent, prometheus.GaugeValue, data.Memory.UsedPercent)
+
+	ch <- prometheus.MustNewConstMetric(metrics.NetBytes, prometheus.CounterValue, float64(data.Network.BytesSent), "sent")
+	ch <- prometheus.MustNewConstMetric(metrics.NetBytes, prometheus.CounterValue, float64(data.Network.BytesRecv), "recv")
+	ch <- prometheus.MustNewConstMetric(metrics.NetPackets, prometheus.CounterValue, float64(data.Network.PacketsSent), "sent")
+	ch <- prometheus.MustNewConstMetric(metrics.NetPackets, prometheus.CounterValue, float64(data.Network.PacketsRecv), "recv")
+}
+
+// collectMetrics runs every enabled collector and assembles the results
+// into a MetricData snapshot. A collector that fails is logged but
+// doesn't prevent the others' samples from being reported.
+func (a *Agent) collectMetrics() (*MetricData, error) {
+	samples, err := a.collectors.CollectAll(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get network metrics: %w", err)
-	}
-
-	// Calculate network deltas
-	var netInfo NetInfo
-	for _, stat := range netStats {
-		if stat.Name == "eth0" || stat.Name == "en0" { // Primary interface
-			if lastStat, exists := a.collector.lastNetStats[stat.Name]; exists {
-				netInfo.BytesSent = stat.BytesSent - lastStat.BytesSent
-				netInfo.BytesRecv = stat.BytesRecv - lastStat.BytesRecv
-				netInfo.PacketsSent = stat.PacketsSent - lastStat.PacketsSent
-				netInfo.PacketsRecv = stat.PacketsRecv - lastStat.PacketsRecv
-			}
-			a.collector.lastNetStats[stat.Name] = stat
-			break
-		}
+		a.logger.WithError(err).Warn("one or more collectors failed")
 	}
-
-	metrics := &MetricData{
-		Timestamp: time.Now(),
-		NodeID:    a.nodeID,
-		CPU: CPUInfo{
-			UsagePercent: cpuPercent[0],
-			LoadAvg:      []float64{loadAvg.Load1, loadAvg.Load5, loadAvg.Load15},
-			Cores:        cpuCount,
-		},
-		Memory: MemInfo{
-			Total:       memInfo.Total,
-			Available:   memInfo.Available,
-			Used:        memInfo.Used,
-			UsedPercent: memInfo.UsedPercent,
-		},
-		Network: netInfo,
+	if len(samples) == 0 && err != nil {
+		return nil, err
 	}
 
-	return metrics, nil
+	data := buildMetricData(a.nodeID, samples)
+	a.collector.last.Store(data)
+	a.ready.Store(true)
+	return data, nil
 }
 
 func (a *Agent) metricsHandler(w http.ResponseWriter, r *http.Request) {
-	metrics, err := a.collectMetrics()
+	data, err := a.collectMetrics()
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to collect metrics")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -146,7 +196,7 @@ func (a *Agent) metricsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(metrics)
+	json.NewEncoder(w).Encode(data)
 }
 
 func (a *Agent) healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -158,35 +208,215 @@ func (a *Agent) healthHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (a *Agent) Start(port string) error {
+// healthzHandler is the liveness probe: it reports healthy as soon as the
+// process is serving requests, regardless of whether a collection has
+// succeeded yet.
+func (a *Agent) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "alive"})
+}
+
+// readyzHandler is the readiness probe: it only reports ready once the
+// first metric collection has succeeded, so a load balancer doesn't send
+// traffic to an agent whose /metrics would still fail.
+func (a *Agent) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if !a.ready.Load() {
+		http.Error(w, `{"status":"not ready"}`, http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
+}
+
+// loggingMiddleware logs one structured entry per request carrying the
+// method, matched route, status and duration fields that the metrics
+// package's latency hook observes, so HTTP instrumentation stays
+// centralized instead of spread across every handler.
+func (a *Agent) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		route := r.URL.Path
+		if m := mux.CurrentRoute(r); m != nil {
+			if tmpl, err := m.GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+		}
+
+		a.logger.WithFields(logrus.Fields{
+			"method":   r.Method,
+			"route":    route,
+			"status":   fmt.Sprintf("%d", rec.status),
+			"duration": time.Since(start).Seconds(),
+		}).Info("Handled request")
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status       int
+	bytesWritten int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytesWritten += int64(n)
+	return n, err
+}
+
+// Flush forwards to the wrapped ResponseWriter when it supports flushing,
+// so streaming handlers behind these middlewares still see an
+// http.Flusher.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// billingMiddleware authenticates a bearer token to a tenant ID and
+// records the request against the per-tenant billing registry. It is a
+// no-op when no tenant tokens are configured, so authentication stays
+// opt-in. It must only wrap the metered endpoints (/metrics,
+// /metrics/stream) — never the exposition endpoints scrapers and probes
+// depend on (/metrics.prom, /metrics/billing, /health, /healthz,
+// /readyz), or a Prometheus scraper and a kubelet probe both start
+// getting 401s the moment tenant tokens are configured.
+func (a *Agent) billingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.tenantAuth == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tenant, err := a.tenantAuth.Tenant(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		bytesIn := r.ContentLength
+		if bytesIn < 0 {
+			bytesIn = 0
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		a.collector.Update(tenant, r.Method, bytesIn, rec.bytesWritten, rec.status/100 != 2)
+	})
+}
+
+// exportersFromEnv builds the set of push-mode Exporters requested via
+// environment variables. Each is optional and independent, so an operator
+// can enable any subset. ctx is shared with any exporter that starts its
+// own background workers (the S3 uploader's retry pool), so canceling it
+// on shutdown stops them too.
+func (a *Agent) exportersFromEnv(ctx context.Context) []exporter.Exporter {
+	var exporters []exporter.Exporter
+
+	if url := os.Getenv("CLOUDPULSE_REMOTE_WRITE_URL"); url != "" {
+		exporters = append(exporters, exporter.NewRemoteWriteExporter(url))
+	}
+
+	if endpoint := os.Getenv("CLOUDPULSE_OTLP_ENDPOINT"); endpoint != "" {
+		exporters = append(exporters, exporter.NewOTLPExporter(endpoint))
+	}
+
+	if bucket := os.Getenv("CLOUDPULSE_S3_BUCKET"); bucket != "" {
+		cfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		if err != nil {
+			a.logger.WithError(err).Error("failed to load AWS config, skipping S3 exporter")
+		} else {
+			prefix := os.Getenv("CLOUDPULSE_S3_PREFIX")
+			if prefix == "" {
+				prefix = a.nodeID
+			}
+			exporters = append(exporters, exporter.NewS3Uploader(ctx, bucket, prefix, s3.NewFromConfig(cfg), a.logger))
+		}
+	}
+
+	return exporters
+}
+
+// startExporters drives exportersFromEnv on CLOUDPULSE_EXPORT_INTERVAL
+// (default 60s) from a background goroutine until ctx is canceled.
+func (a *Agent) startExporters(ctx context.Context) {
+	exporters := a.exportersFromEnv(ctx)
+
+	interval := 60 * time.Second
+	if raw := os.Getenv("CLOUDPULSE_EXPORT_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			interval = d
+		} else {
+			a.logger.WithError(err).Warn("invalid CLOUDPULSE_EXPORT_INTERVAL, using default")
+		}
+	}
+
+	manager := exporter.NewManager(interval, a.logger, exporters...)
+	go manager.Run(ctx, a.collectMetrics)
+}
+
+// Name implements service.Service.
+func (a *Agent) Name() string { return "agent" }
+
+// Start implements service.Service: it builds the HTTP router, starts the
+// exporter and sampler background goroutines, and serves until Stop shuts
+// the server down or it fails for another reason. Canceling ctx stops the
+// background goroutines; the server itself stops only through Stop.
+func (a *Agent) Start(ctx context.Context) error {
 	router := mux.NewRouter()
-	router.HandleFunc("/metrics", a.metricsHandler).Methods("GET")
+	router.Handle("/metrics", a.billingMiddleware(http.HandlerFunc(a.metricsHandler))).Methods("GET")
+	router.Handle("/metrics.prom", promhttp.HandlerFor(a.promRegistry, promhttp.HandlerOpts{})).Methods("GET")
+	router.Handle("/metrics/billing", promhttp.HandlerFor(a.billing.Registerer(), promhttp.HandlerOpts{})).Methods("GET")
+	router.Handle("/metrics/stream", a.billingMiddleware(http.HandlerFunc(a.streamHandler))).Methods("GET")
 	router.HandleFunc("/health", a.healthHandler).Methods("GET")
+	router.HandleFunc("/healthz", a.healthzHandler).Methods("GET")
+	router.HandleFunc("/readyz", a.readyzHandler).Methods("GET")
+	router.Use(a.loggingMiddleware)
 
 	a.server = &http.Server{
-		Addr:    ":" + port,
+		Addr:    ":" + a.port,
 		Handler: router,
 	}
 
-	a.logger.WithField("port", port).Info("Starting CloudPulse agent")
-	return a.server.ListenAndServe()
+	a.startExporters(ctx)
+	go a.runSampler(ctx)
+
+	a.logger.WithField("port", a.port).Info("Starting CloudPulse agent")
+	if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
-func main() {
-	nodeID := os.Getenv("NODE_ID")
-	if nodeID == "" {
-		hostname, err := os.Hostname()
-		if err != nil {
-			log.Fatal("Failed to get hostname and NODE_ID not set")
-		}
-		nodeID = hostname
+// Stop implements service.Service, gracefully shutting down the HTTP
+// server within the deadline ctx carries.
+func (a *Agent) Stop(ctx context.Context) error {
+	if a.server == nil {
+		return nil
 	}
+	a.logger.Info("Stopping CloudPulse agent")
+	return a.server.Shutdown(ctx)
+}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+func main() {
+	cfg, err := config.Load(os.Getenv("CLOUDPULSE_CONFIG"))
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
 	}
 
-	agent := NewAgent(nodeID)
-	log.Fatal(agent.Start(port))
+	agent := NewAgent(cfg.NodeID, cfg.Port)
+	runner := service.NewRunner(agent.logger, cfg.ShutdownTimeout, agent)
+
+	if err := runner.Run(context.Background()); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,22 @@
+package metrics
+
+// Description is a stable, serializable summary of one registered metric.
+// It is the unit the machine-readable catalog is built from, so
+// downstream users can diff catalogs between releases and catch
+// accidental renames or removals.
+type Description struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Help   string   `json:"help"`
+	Labels []string `json:"labels"`
+}
+
+// Describe returns the full metric catalog in registration order. It is
+// derived from catalog, which newDesc (and this package's init) populate
+// as each metric is actually constructed, so a metric can't be added here
+// without being documented.
+func Describe() []Description {
+	out := make([]Description, len(catalog))
+	copy(out, catalog)
+	return out
+}
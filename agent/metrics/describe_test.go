@@ -0,0 +1,32 @@
+package metrics
+
+import "testing"
+
+// TestDescribeAll ensures every entry in the catalog carries documentation,
+// so a new metric can't be registered without a name, type, help text and
+// (where it has dimensions) a documented label set.
+func TestDescribeAll(t *testing.T) {
+	seen := make(map[string]bool)
+
+	for _, d := range Describe() {
+		if d.Name == "" {
+			t.Fatalf("catalog entry with empty Name: %+v", d)
+		}
+		if seen[d.Name] {
+			t.Fatalf("duplicate catalog entry for %q", d.Name)
+		}
+		seen[d.Name] = true
+
+		if d.Type == "" {
+			t.Errorf("metric %q: missing Type", d.Name)
+		}
+		if d.Help == "" {
+			t.Errorf("metric %q: missing Help text", d.Name)
+		}
+		for _, l := range d.Labels {
+			if l == "" {
+				t.Errorf("metric %q: has an undocumented (empty) label", d.Name)
+			}
+		}
+	}
+}
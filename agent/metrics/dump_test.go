@@ -0,0 +1,33 @@
+//go:build dump_metrics
+
+package metrics
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+var dumpPath = flag.String("metrics.dump-path", "metrics_catalog.json", "file to write the metric catalog JSON to")
+
+// TestDumpMetrics writes the full metric catalog to -metrics.dump-path as
+// stable JSON, in the registration order Describe returns. It is only built with -tags=dump_metrics so it
+// doesn't run as part of the normal test suite; CI runs it on each release
+// branch and diffs the output against the previous release to catch
+// accidental metric renames or removals.
+func TestDumpMetrics(t *testing.T) {
+	catalog := Describe()
+
+	f, err := os.Create(*dumpPath)
+	if err != nil {
+		t.Fatalf("create %s: %v", *dumpPath, err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(catalog); err != nil {
+		t.Fatalf("encode catalog: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// Package metrics provides the Prometheus instrumentation for the
+// CloudPulse agent: descriptors for the system gauges fed by the agent's
+// MetricCollector, and HTTP server instrumentation modeled on Arvados's
+// httpserver.metrics hook, which derives request latency histograms from
+// structured log entries instead of wrapping every handler by hand.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const namespace = "cloudpulse"
+
+const (
+	httpSubsystem    = "http"
+	httpDurationName = "request_duration_seconds"
+	httpDurationHelp = "HTTP request latency in seconds."
+)
+
+// catalog records the Description for every metric defined below. Entries
+// for newDesc metrics come from the same call that builds the descriptor;
+// the HTTP latency histogram's entry is added in init from the name and
+// help constants NewHTTPMetrics also uses, with its own copy of the label
+// list.
+var catalog []Description
+
+// newDesc builds a *prometheus.Desc and records its Description in
+// catalog in the same call, so a new metric can't be added here without
+// also documenting it.
+func newDesc(subsystem, name, metricType, help string, labels []string) *prometheus.Desc {
+	fqName := prometheus.BuildFQName(namespace, subsystem, name)
+	catalog = append(catalog, Description{Name: fqName, Type: metricType, Help: help, Labels: labels})
+	return prometheus.NewDesc(fqName, help, labels, nil)
+}
+
+// System metric descriptors. These are shared between the JSON
+// /metrics handler's MetricCollector and its Prometheus Describe/Collect
+// implementation so both surfaces report the same numbers.
+var (
+	CPUUsagePercent = newDesc("cpu", "usage_percent", "gauge", "Total CPU usage percentage across all cores.", nil)
+	CPULoad         = newDesc("cpu", "load_average", "gauge", "System load average.", []string{"period"})
+	CPUCores        = newDesc("cpu", "cores", "gauge", "Number of logical CPU cores.", nil)
+	MemTotal        = newDesc("memory", "total_bytes", "gauge", "Total physical memory in bytes.", nil)
+	MemAvailable    = newDesc("memory", "available_bytes", "gauge", "Available physical memory in bytes.", nil)
+	MemUsed         = newDesc("memory", "used_bytes", "gauge", "Used physical memory in bytes.", nil)
+	MemUsedPercent  = newDesc("memory", "used_percent", "gauge", "Used physical memory percentage.", nil)
+	NetBytes        = newDesc("network", "bytes_total", "counter", "Network bytes transferred since the previous collection.", []string{"direction"})
+	NetPackets      = newDesc("network", "packets_total", "counter", "Network packets transferred since the previous collection.", []string{"direction"})
+)
+
+// HTTPMetrics holds the request instrumentation registered against a
+// Registry and the logrus hook that feeds it.
+type HTTPMetrics struct {
+	duration *prometheus.HistogramVec
+}
+
+// NewHTTPMetrics registers the HTTP latency histogram on reg and returns
+// the instrumentation handle.
+func NewHTTPMetrics(reg *prometheus.Registry) *HTTPMetrics {
+	h := &HTTPMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: httpSubsystem,
+			Name:      httpDurationName,
+			Help:      httpDurationHelp,
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+	reg.MustRegister(h.duration)
+	return h
+}
+
+// Hook returns a logrus.Hook that observes request latency off the
+// "method", "route", "status" and "duration" fields of log entries,
+// following the Arvados httpserver.metrics pattern: the HTTP logging
+// middleware already logs one structured entry per request, so the hook
+// rides along rather than requiring every handler to call an Observe
+// itself.
+func (m *HTTPMetrics) Hook() logrus.Hook {
+	return &latencyHook{metrics: m}
+}
+
+type latencyHook struct {
+	metrics *HTTPMetrics
+}
+
+func (h *latencyHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *latencyHook) Fire(entry *logrus.Entry) error {
+	method, ok := entry.Data["method"].(string)
+	if !ok {
+		return nil
+	}
+	route, _ := entry.Data["route"].(string)
+	status, _ := entry.Data["status"].(string)
+	duration, ok := entry.Data["duration"].(float64)
+	if !ok {
+		return nil
+	}
+	h.metrics.duration.WithLabelValues(method, route, status).Observe(duration)
+	return nil
+}
+
+func init() {
+	catalog = append(catalog, Description{
+		Name:   prometheus.BuildFQName(namespace, httpSubsystem, httpDurationName),
+		Type:   "histogram",
+		Help:   httpDurationHelp,
+		Labels: []string{"method", "route", "status"},
+	})
+}
@@ -0,0 +1,98 @@
+// Package model holds the data types shared between the agent's
+// collection, exposition and export paths. It was split out of package
+// main so that subpackages such as exporter can depend on MetricData
+// without importing the main package (which Go forbids).
+package model
+
+import "time"
+
+// MetricData represents collected system metrics. The slice fields are
+// populated by the agent's pluggable Collector plane and are omitted when
+// the corresponding collector is disabled.
+type MetricData struct {
+	Timestamp time.Time `json:"timestamp"`
+	NodeID    string    `json:"node_id"`
+	CPU       CPUInfo   `json:"cpu"`
+	Memory    MemInfo   `json:"memory"`
+	Network   NetInfo   `json:"network"`
+
+	CPUCores   []CPUCoreInfo   `json:"cpu_cores,omitempty"`
+	Interfaces []InterfaceInfo `json:"interfaces,omitempty"`
+	Disks      []DiskInfo      `json:"disks,omitempty"`
+	Processes  []ProcessInfo   `json:"processes,omitempty"`
+	Sockets    *SocketInfo     `json:"sockets,omitempty"`
+}
+
+// Sample is a single named measurement produced by a Collector, generic
+// enough to cover gauges and counters across every collector without each
+// one needing its own exported result type.
+type Sample struct {
+	Name   string            `json:"name"`
+	Value  float64           `json:"value"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// CPUCoreInfo is one logical core's usage, from the per-core CPU
+// collector.
+type CPUCoreInfo struct {
+	Core         int     `json:"core"`
+	UsagePercent float64 `json:"usage_percent"`
+}
+
+// InterfaceInfo is one network interface's deltas since the previous
+// collection, from the per-interface network collector.
+type InterfaceInfo struct {
+	Name        string `json:"name"`
+	BytesSent   uint64 `json:"bytes_sent"`
+	BytesRecv   uint64 `json:"bytes_recv"`
+	PacketsSent uint64 `json:"packets_sent"`
+	PacketsRecv uint64 `json:"packets_recv"`
+}
+
+// DiskInfo is one mounted filesystem's usage and cumulative IOPS counters,
+// from the disk collector.
+type DiskInfo struct {
+	Device      string  `json:"device"`
+	Mountpoint  string  `json:"mountpoint"`
+	UsedPercent float64 `json:"used_percent"`
+	ReadCount   uint64  `json:"read_count"`
+	WriteCount  uint64  `json:"write_count"`
+	ReadBytes   uint64  `json:"read_bytes"`
+	WriteBytes  uint64  `json:"write_bytes"`
+}
+
+// ProcessInfo is one process's resource usage, from the top-N process
+// collector.
+type ProcessInfo struct {
+	PID        int32   `json:"pid"`
+	Name       string  `json:"name"`
+	CPUPercent float64 `json:"cpu_percent"`
+	RSSBytes   uint64  `json:"rss_bytes"`
+}
+
+// SocketInfo is the system-wide open socket count, from the socket
+// collector.
+type SocketInfo struct {
+	TCP int `json:"tcp"`
+	UDP int `json:"udp"`
+}
+
+type CPUInfo struct {
+	UsagePercent float64   `json:"usage_percent"`
+	LoadAvg      []float64 `json:"load_avg"`
+	Cores        int       `json:"cores"`
+}
+
+type MemInfo struct {
+	Total       uint64  `json:"total"`
+	Available   uint64  `json:"available"`
+	Used        uint64  `json:"used"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+type NetInfo struct {
+	BytesSent   uint64 `json:"bytes_sent"`
+	BytesRecv   uint64 `json:"bytes_recv"`
+	PacketsSent uint64 `json:"packets_sent"`
+	PacketsRecv uint64 `json:"packets_recv"`
+}
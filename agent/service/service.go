@@ -0,0 +1,93 @@
+// Package service provides a small, reusable runner for CloudPulse's
+// long-running components, mirroring the Arvados lib/service refactor:
+// signal trapping, bounded graceful shutdown, and structured
+// startup/shutdown logging, so future daemons (an aggregator, a gateway)
+// can plug in the same way the Agent does today.
+package service
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Service is a long-running component the Runner manages.
+type Service interface {
+	// Name identifies the service in logs.
+	Name() string
+	// Start runs the service until ctx is canceled or it exits on its
+	// own (returning nil on a clean exit, an error otherwise).
+	Start(ctx context.Context) error
+	// Stop asks the service to shut down, returning once it has or ctx
+	// expires.
+	Stop(ctx context.Context) error
+}
+
+// Runner starts a set of Services, traps SIGINT/SIGTERM, and drives a
+// bounded graceful shutdown when a signal arrives or any service exits
+// on its own.
+type Runner struct {
+	services        []Service
+	logger          *logrus.Logger
+	shutdownTimeout time.Duration
+}
+
+// NewRunner builds a Runner over services, allowing shutdownTimeout for
+// every service's Stop to return once shutdown begins.
+func NewRunner(logger *logrus.Logger, shutdownTimeout time.Duration, services ...Service) *Runner {
+	return &Runner{services: services, logger: logger, shutdownTimeout: shutdownTimeout}
+}
+
+type result struct {
+	name string
+	err  error
+}
+
+// Run starts every service and blocks until a shutdown signal arrives or
+// a service exits on its own, then stops every service within the
+// runner's shutdown timeout.
+func (r *Runner) Run(parent context.Context) error {
+	ctx, stop := signal.NotifyContext(parent, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	exited := make(chan result, len(r.services))
+	for _, svc := range r.services {
+		svc := svc
+		r.logger.WithField("service", svc.Name()).Info("starting service")
+		go func() {
+			exited <- result{name: svc.Name(), err: svc.Start(ctx)}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		r.logger.Info("shutdown signal received")
+	case res := <-exited:
+		if res.err != nil {
+			r.logger.WithError(res.err).WithField("service", res.name).Error("service exited unexpectedly, shutting down")
+		} else {
+			r.logger.WithField("service", res.name).Info("service exited, shutting down")
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), r.shutdownTimeout)
+	defer cancel()
+
+	var failed []string
+	for _, svc := range r.services {
+		r.logger.WithField("service", svc.Name()).Info("stopping service")
+		if err := svc.Stop(shutdownCtx); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", svc.Name(), err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("shutdown errors: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TenantAuth maps a bearer token to the tenant ID it authenticates as.
+// Tokens are provisioned out of band (env/config); there is no token
+// issuance in this package.
+type TenantAuth struct {
+	tokens map[string]string // token -> tenant ID
+}
+
+// NewTenantAuth builds a TenantAuth from a token-to-tenant map.
+func NewTenantAuth(tokens map[string]string) *TenantAuth {
+	return &TenantAuth{tokens: tokens}
+}
+
+// ParseTokens parses the "token:tenant,token:tenant" format used by the
+// CLOUDPULSE_TENANT_TOKENS environment variable.
+func ParseTokens(raw string) map[string]string {
+	tokens := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		tokens[parts[0]] = parts[1]
+	}
+	return tokens
+}
+
+// Tenant resolves the bearer token on r to a tenant ID. It returns an
+// error if the Authorization header is missing, malformed, or the token
+// is unknown.
+func (a *TenantAuth) Tenant(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", fmt.Errorf("missing Authorization header")
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", fmt.Errorf("Authorization header must use the Bearer scheme")
+	}
+
+	token := strings.TrimPrefix(header, prefix)
+	tenant, ok := a.tokens[token]
+	if !ok {
+		return "", fmt.Errorf("unknown bearer token")
+	}
+	return tenant, nil
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseTokens(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want map[string]string
+	}{
+		{name: "empty string yields no tokens", raw: "", want: map[string]string{}},
+		{name: "single pair", raw: "abc123:tenant-a", want: map[string]string{"abc123": "tenant-a"}},
+		{
+			name: "multiple pairs",
+			raw:  "abc123:tenant-a,def456:tenant-b",
+			want: map[string]string{"abc123": "tenant-a", "def456": "tenant-b"},
+		},
+		{
+			name: "whitespace around pairs and entries is trimmed",
+			raw:  " abc123:tenant-a , def456:tenant-b ",
+			want: map[string]string{"abc123": "tenant-a", "def456": "tenant-b"},
+		},
+		{name: "blank entries between commas are skipped", raw: "abc123:tenant-a,,def456:tenant-b", want: map[string]string{"abc123": "tenant-a", "def456": "tenant-b"}},
+		{name: "entry without a colon is skipped", raw: "not-a-pair,abc123:tenant-a", want: map[string]string{"abc123": "tenant-a"}},
+		{name: "tenant value may itself contain a colon", raw: "abc123:tenant:a", want: map[string]string{"abc123": "tenant:a"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ParseTokens(tc.raw)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ParseTokens(%q) = %#v, want %#v", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTenantAuth_Tenant(t *testing.T) {
+	auth := NewTenantAuth(map[string]string{"good-token": "tenant-a"})
+
+	cases := []struct {
+		name       string
+		authHeader string
+		wantTenant string
+		wantErr    bool
+	}{
+		{name: "missing Authorization header", authHeader: "", wantErr: true},
+		{name: "wrong scheme", authHeader: "Basic good-token", wantErr: true},
+		{name: "unknown token", authHeader: "Bearer unknown-token", wantErr: true},
+		{name: "known token resolves its tenant", authHeader: "Bearer good-token", wantTenant: "tenant-a"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+			if err != nil {
+				t.Fatalf("build request: %v", err)
+			}
+			if tc.authHeader != "" {
+				req.Header.Set("Authorization", tc.authHeader)
+			}
+
+			tenant, err := auth.Tenant(req)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Tenant() = %q, nil error, want an error", tenant)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Tenant() returned error: %v", err)
+			}
+			if tenant != tc.wantTenant {
+				t.Errorf("Tenant() = %q, want %q", tenant, tc.wantTenant)
+			}
+		})
+	}
+}
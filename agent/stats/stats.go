@@ -0,0 +1,73 @@
+// Package stats tracks per-tenant request accounting for CloudPulse's
+// authenticated HTTP surface, inspired by the frostfs UsersStat design: a
+// labeled Prometheus registry operators can scrape independently of the
+// host metrics to bill or rate-limit individual consumers.
+package stats
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const namespace = "cloudpulse"
+
+// Registry holds the per-tenant counters served at /metrics/billing. It
+// is a distinct prometheus.Registry from the host metrics one so
+// operators can scrape or secure it separately.
+type Registry struct {
+	reg *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	bytesIn  *prometheus.CounterVec
+	bytesOut *prometheus.CounterVec
+}
+
+// NewRegistry creates and registers the billing counters.
+func NewRegistry() *Registry {
+	s := &Registry{
+		reg: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "billing",
+			Name:      "requests_total",
+			Help:      "Requests served, by tenant and HTTP method.",
+		}, []string{"tenant", "op"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "billing",
+			Name:      "errors_total",
+			Help:      "Requests that returned a non-2xx status, by tenant and HTTP method.",
+		}, []string{"tenant", "op"}),
+		bytesIn: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "billing",
+			Name:      "bytes_in_total",
+			Help:      "Request bytes received, by tenant.",
+		}, []string{"tenant"}),
+		bytesOut: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "billing",
+			Name:      "bytes_out_total",
+			Help:      "Response bytes sent, by tenant.",
+		}, []string{"tenant"}),
+	}
+
+	s.reg.MustRegister(s.requests, s.errors, s.bytesIn, s.bytesOut)
+	return s
+}
+
+// Registerer exposes the underlying registry so it can be mounted behind
+// promhttp at /metrics/billing.
+func (s *Registry) Registerer() *prometheus.Registry {
+	return s.reg
+}
+
+// Update records one request: op is typically the HTTP method, bytesIn and
+// bytesOut the request/response sizes, and isError whether the response
+// status was non-2xx.
+func (s *Registry) Update(tenant, op string, bytesIn, bytesOut int64, isError bool) {
+	s.requests.WithLabelValues(tenant, op).Inc()
+	s.bytesIn.WithLabelValues(tenant).Add(float64(bytesIn))
+	s.bytesOut.WithLabelValues(tenant).Add(float64(bytesOut))
+	if isError {
+		s.errors.WithLabelValues(tenant, op).Inc()
+	}
+}
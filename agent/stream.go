@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStreamRate        = time.Second
+	defaultStreamMaxDuration = 5 * time.Minute
+)
+
+// subscriber is one /metrics/stream client's feed of samples.
+type subscriber chan *MetricData
+
+// broadcaster fans a single sampler goroutine's output out to any number
+// of subscribers, so N concurrent stream clients never trigger N
+// concurrent metric collections.
+type broadcaster struct {
+	mu   sync.Mutex
+	subs map[subscriber]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subs: make(map[subscriber]struct{})}
+}
+
+func (b *broadcaster) subscribe() subscriber {
+	ch := make(subscriber, 1)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *broadcaster) unsubscribe(ch subscriber) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+	close(ch)
+}
+
+// publish fans data out to every subscriber, dropping it for any
+// subscriber that isn't ready rather than blocking the sampler.
+func (b *broadcaster) publish(data *MetricData) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- data:
+		default:
+		}
+	}
+}
+
+// runSampler is the single goroutine that collects metrics and publishes
+// them to the broadcaster, regardless of how many /metrics/stream clients
+// are attached.
+func (a *Agent) runSampler(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			data, err := a.collectMetrics()
+			if err != nil {
+				a.logger.WithError(err).Error("sampler: failed to collect metrics")
+				continue
+			}
+			a.stream.publish(data)
+		}
+	}
+}
+
+// streamHandler serves a long-lived feed of MetricData frames, as either
+// Server-Sent Events (default) or chunked NDJSON (?format=ndjson). The
+// ?rate query param controls how often a frame is emitted to this client
+// (default 1s); ?max_duration bounds the connection's total lifetime
+// (default 5m) so a forgotten client doesn't hold a connection forever.
+func (a *Agent) streamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	rate := defaultStreamRate
+	if raw := r.URL.Query().Get("rate"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			rate = d
+		}
+	}
+
+	maxDuration := defaultStreamMaxDuration
+	if raw := r.URL.Query().Get("max_duration"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			maxDuration = d
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), maxDuration)
+	defer cancel()
+
+	sub := a.stream.subscribe()
+	defer a.stream.unsubscribe(sub)
+
+	ndjson := r.URL.Query().Get("format") == "ndjson"
+	if ndjson {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(rate)
+	defer ticker.Stop()
+
+	var last *MetricData
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case data := <-sub:
+			last = data
+		case <-ticker.C:
+			if last == nil {
+				continue
+			}
+			body, err := json.Marshal(last)
+			if err != nil {
+				a.logger.WithError(err).Error("stream: failed to marshal frame")
+				continue
+			}
+			if ndjson {
+				w.Write(body)
+				w.Write([]byte("\n"))
+			} else {
+				fmt.Fprintf(w, "data: %s\n\n", body)
+			}
+			flusher.Flush()
+		}
+	}
+}